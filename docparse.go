@@ -0,0 +1,377 @@
+package huma
+
+import (
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Recognized comment annotations, layered on top of struct tags. Tags win
+// on conflict: an annotation only fills in a field the tags left empty.
+var (
+	annotationDescription = regexp.MustCompile(`(?m)^\s*@Description\s+(.+)$`)
+	annotationExample     = regexp.MustCompile(`(?m)^\s*@Example\(([^)]*)\)\s*$`)
+	annotationEnum        = regexp.MustCompile(`(?m)^\s*@Enum\(([^)]*)\)\s*$`)
+	annotationFormat      = regexp.MustCompile(`(?m)^\s*@Format\(([^)]*)\)\s*$`)
+	annotationDeprecated  = regexp.MustCompile(`(?m)^\s*@Deprecated\s*$`)
+	annotationLine        = regexp.MustCompile(`(?m)^\s*@\w+(\([^)]*\))?\s*$`)
+)
+
+// GenerateSchemaFromPackage parses the Go source package at pkgPath and
+// builds a Schema for the exported struct type named typeName, folding
+// its doc comments (and its fields' doc comments) into the schema
+// instead of requiring everything to live in struct tags. A small
+// annotation vocabulary is recognized in those comments - @Description,
+// @Example(value), @Enum(a,b,c), @Format(uuid), @Deprecated - and is
+// layered under the existing struct tags, which win on conflict.
+//
+// Types are resolved within pkgPath only; a field referencing a type
+// from another package returns an error, except for time.Time which is
+// special-cased the same way GenerateSchema handles it.
+func GenerateSchemaFromPackage(pkgPath string, typeName string) (*Schema, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, pkgPath, nil, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parse package %s: %w", pkgPath, err)
+	}
+
+	for name, pkg := range pkgs {
+		if strings.HasSuffix(name, "_test") {
+			continue
+		}
+
+		docPkg := doc.New(pkg, pkgPath, doc.AllDecls)
+		b := &docSchemaBuilder{
+			types: make(map[string]*doc.Type, len(docPkg.Types)),
+			// Inline, like GenerateSchema: the builder's registry isn't
+			// exposed to the caller, so a $ref into it would be
+			// unresolvable. Only a genuine cycle still breaks via $ref.
+			reg: newInlineRegistry(),
+		}
+		for _, t := range docPkg.Types {
+			b.types[t.Name] = t
+		}
+
+		if dt, ok := b.types[typeName]; ok {
+			return b.schemaForType(dt)
+		}
+	}
+
+	return nil, fmt.Errorf("type %s not found in package %s", typeName, pkgPath)
+}
+
+// docSchemaBuilder generates schemas from the AST of a single parsed
+// package, breaking cycles in locally-defined named types through reg
+// the same way schemaOrRef does for reflect-based generation.
+type docSchemaBuilder struct {
+	types map[string]*doc.Type
+	reg   *SchemaRegistry
+}
+
+// schemaForType builds the full, inlined schema for a struct type found
+// via go/doc, folding in its doc comment. Nested occurrences of named
+// types go through namedTypeSchema instead, which inlines them too and
+// only returns a `$ref` to break a cycle.
+func (b *docSchemaBuilder) schemaForType(dt *doc.Type) (*Schema, error) {
+	structType, err := structTypeSpec(dt)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &Schema{Type: "object"}
+	properties := make(map[string]*Schema)
+	required := make([]string, 0)
+
+	for _, field := range structType.Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			unquoted, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			tag = reflect.StructTag(unquoted)
+		}
+
+		jsonTag, _ := tag.Lookup("json")
+		jsonParts := strings.Split(jsonTag, ",")
+
+		if len(field.Names) == 0 && jsonParts[0] == "" {
+			// Embedded field: contribute via allOf, mirroring how
+			// GenerateSchema treats embedded structs.
+			embedded, err := b.schemaForExpr(field.Type)
+			if err != nil {
+				return nil, err
+			}
+			schema.AllOf = append(schema.AllOf, embedded)
+			continue
+		}
+
+		name := ""
+		if len(field.Names) > 0 {
+			name = field.Names[0].Name
+		}
+		if jsonParts[0] != "" {
+			name = jsonParts[0]
+		}
+
+		fieldSchema, err := b.schemaForExpr(field.Type)
+		if err != nil {
+			return nil, err
+		}
+		// A bare $ref can't carry the tag/doc-comment metadata below as
+		// sibling keys, so detach it into an allOf wrapper first,
+		// mirroring schema.go's reflect-based field loop.
+		fieldSchema = detachRef(fieldSchema)
+
+		_, isPtr := field.Type.(*ast.StarExpr)
+		if isPtr {
+			fieldSchema.Nullable = true
+		}
+
+		if d, ok := tag.Lookup("description"); ok {
+			fieldSchema.Description = d
+		}
+		if fm, ok := tag.Lookup("format"); ok {
+			fieldSchema.Format = fm
+		}
+		if dep, ok := tag.Lookup("deprecated"); ok {
+			fieldSchema.Deprecated = dep == "true"
+		}
+		if en, ok := tag.Lookup("enum"); ok {
+			fieldSchema.Enum = nil
+			for _, v := range strings.Split(en, ",") {
+				parsed, err := getTagValue(fieldSchema, v)
+				if err != nil {
+					return nil, err
+				}
+				fieldSchema.Enum = append(fieldSchema.Enum, parsed)
+			}
+		}
+		if def, ok := tag.Lookup("default"); ok {
+			v, err := getTagValue(fieldSchema, def)
+			if err != nil {
+				return nil, err
+			}
+			fieldSchema.Default = v
+		}
+		if ex, ok := tag.Lookup("example"); ok {
+			v, err := getTagValue(fieldSchema, ex)
+			if err != nil {
+				return nil, err
+			}
+			fieldSchema.Example = v
+		}
+
+		fieldDoc := ""
+		if field.Doc != nil {
+			fieldDoc = field.Doc.Text()
+		} else if field.Comment != nil {
+			fieldDoc = field.Comment.Text()
+		}
+		if fieldDoc != "" {
+			if err := applyAnnotations(fieldSchema, fieldDoc); err != nil {
+				return nil, err
+			}
+		}
+
+		properties[name] = fieldSchema
+
+		// A pointer field is nullable, so - like an explicit omitempty -
+		// it's not required to be present, matching the reflect path.
+		optional := isPtr
+		for _, part := range jsonParts[1:] {
+			if part == "omitempty" {
+				optional = true
+			}
+		}
+		if !optional {
+			required = append(required, name)
+		}
+	}
+
+	if len(properties) > 0 {
+		schema.Properties = properties
+	}
+	if len(required) > 0 {
+		schema.Required = required
+	}
+
+	if err := applyAnnotations(schema, dt.Doc); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// namedTypeSchema resolves a reference to a locally-declared named type,
+// mirroring schemaOrRef: with b.reg.dedupe unset (the default, since
+// GenerateSchemaFromPackage's registry isn't reachable by its caller) a
+// repeated occurrence is regenerated inline, and only a type that refers
+// back to itself breaks its cycle with a `$ref`.
+func (b *docSchemaBuilder) namedTypeSchema(name string, dt *doc.Type) (*Schema, error) {
+	if b.reg.inProgress[name] {
+		return refSchema(name), nil
+	}
+
+	if b.reg.dedupe {
+		if _, ok := b.reg.Definitions[name]; ok {
+			return refSchema(name), nil
+		}
+	}
+
+	b.reg.inProgress[name] = true
+	s, err := b.schemaForType(dt)
+	delete(b.reg.inProgress, name)
+	if err != nil {
+		return nil, err
+	}
+
+	b.reg.Definitions[name] = s
+
+	if b.reg.dedupe {
+		return refSchema(name), nil
+	}
+	return s, nil
+}
+
+// schemaForExpr maps an AST type expression to a Schema, resolving named
+// types declared in the same package through namedTypeSchema.
+func (b *docSchemaBuilder) schemaForExpr(expr ast.Expr) (*Schema, error) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if s, ok := basicSchemaForIdent(e.Name); ok {
+			return s, nil
+		}
+		if dt, ok := b.types[e.Name]; ok {
+			return b.namedTypeSchema(e.Name, dt)
+		}
+		return nil, fmt.Errorf("unsupported type %s", e.Name)
+	case *ast.StarExpr:
+		// Nullable is set by the struct-field loop instead, which knows
+		// whether it's looking at a bare $ref that needs detaching
+		// first; setting it here could put it next to a $ref with
+		// nothing to detach it (e.g. a slice of pointers to a
+		// self-referential type), silently dropping it just like an
+		// undetached description or format would be.
+		return b.schemaForExpr(e.X)
+	case *ast.ArrayType:
+		elem, err := b.schemaForExpr(e.Elt)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: elem}, nil
+	case *ast.MapType:
+		if _, ok := e.Key.(*ast.Ident); !ok {
+			return nil, fmt.Errorf("unsupported map key type %T", e.Key)
+		}
+		elem, err := b.schemaForExpr(e.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: elem}, nil
+	case *ast.SelectorExpr:
+		if pkg, ok := e.X.(*ast.Ident); ok && pkg.Name == "time" && e.Sel.Name == "Time" {
+			return &Schema{Type: "string", Format: "date-time"}, nil
+		}
+		return nil, fmt.Errorf("unsupported external type %s.%s", e.X, e.Sel.Name)
+	case *ast.InterfaceType:
+		return &Schema{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported field type expression %T", expr)
+	}
+}
+
+// basicSchemaForIdent maps a builtin Go type name to its schema.
+func basicSchemaForIdent(name string) (*Schema, bool) {
+	switch name {
+	case "string":
+		return &Schema{Type: "string"}, true
+	case "bool":
+		return &Schema{Type: "boolean"}, true
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64":
+		return &Schema{Type: "integer"}, true
+	case "float32", "float64":
+		return &Schema{Type: "number"}, true
+	}
+	return nil, false
+}
+
+// structTypeSpec finds the *ast.StructType backing a go/doc type.
+func structTypeSpec(dt *doc.Type) (*ast.StructType, error) {
+	for _, spec := range dt.Decl.Specs {
+		ts, ok := spec.(*ast.TypeSpec)
+		if !ok || ts.Name.Name != dt.Name {
+			continue
+		}
+		st, ok := ts.Type.(*ast.StructType)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a struct type", dt.Name)
+		}
+		return st, nil
+	}
+	return nil, fmt.Errorf("could not find type spec for %s", dt.Name)
+}
+
+// applyAnnotations folds the @Description/@Example/@Enum/@Format/
+// @Deprecated annotation vocabulary found in a doc comment into s,
+// without overwriting anything a struct tag already set. If no
+// @Description annotation is present, the comment text (with annotation
+// lines stripped) is used as the description instead.
+func applyAnnotations(s *Schema, docComment string) error {
+	if m := annotationDescription.FindStringSubmatch(docComment); m != nil {
+		if s.Description == "" {
+			s.Description = strings.TrimSpace(m[1])
+		}
+	} else if s.Description == "" {
+		if plain := stripAnnotations(docComment); plain != "" {
+			s.Description = plain
+		}
+	}
+
+	if s.Example == nil {
+		if m := annotationExample.FindStringSubmatch(docComment); m != nil {
+			v, err := getTagValue(s, strings.TrimSpace(m[1]))
+			if err != nil {
+				return err
+			}
+			s.Example = v
+		}
+	}
+
+	if len(s.Enum) == 0 {
+		if m := annotationEnum.FindStringSubmatch(docComment); m != nil {
+			for _, raw := range strings.Split(m[1], ",") {
+				v, err := getTagValue(s, strings.TrimSpace(raw))
+				if err != nil {
+					return err
+				}
+				s.Enum = append(s.Enum, v)
+			}
+		}
+	}
+
+	if s.Format == "" {
+		if m := annotationFormat.FindStringSubmatch(docComment); m != nil {
+			s.Format = strings.TrimSpace(m[1])
+		}
+	}
+
+	if !s.Deprecated && annotationDeprecated.MatchString(docComment) {
+		s.Deprecated = true
+	}
+
+	return nil
+}
+
+// stripAnnotations removes recognized annotation lines from a doc
+// comment, leaving the remaining prose to use as a fallback description.
+func stripAnnotations(docComment string) string {
+	return strings.TrimSpace(annotationLine.ReplaceAllString(docComment, ""))
+}
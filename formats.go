@@ -0,0 +1,145 @@
+package huma
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+// FormatChecker validates that a decoded value conforms to a named string
+// format, e.g. "date-time" or "uuid". Register custom checkers with
+// RegisterFormat; GenerateSchema uses them to catch invalid `default` and
+// `example` tag values at generation time, and (*Schema).Validate uses
+// them to check arbitrary values against a schema's `format`.
+type FormatChecker interface {
+	IsFormat(v interface{}) bool
+}
+
+// formatCheckerFunc adapts a plain function to the FormatChecker interface.
+type formatCheckerFunc func(v interface{}) bool
+
+func (f formatCheckerFunc) IsFormat(v interface{}) bool {
+	return f(v)
+}
+
+// formatCheckers holds every checker registered via RegisterFormat, keyed
+// by the `format` name it validates.
+var formatCheckers = map[string]FormatChecker{}
+
+// RegisterFormat registers (or overrides) the checker used to validate
+// values for the named `format` keyword.
+func RegisterFormat(name string, checker FormatChecker) {
+	formatCheckers[name] = checker
+}
+
+// checkFormatValue validates v, parsed from the given struct tag, against
+// the checker registered for s.Format. It's a no-op if s.Format has no
+// registered checker.
+func checkFormatValue(s *Schema, tagName, raw string, v interface{}) error {
+	checker, ok := formatCheckers[s.Format]
+	if !ok {
+		return nil
+	}
+	if !checker.IsFormat(v) {
+		return fmt.Errorf("%s value %q does not match format %q", tagName, raw, s.Format)
+	}
+	return nil
+}
+
+var (
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hostnamePattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+)
+
+func init() {
+	RegisterFormat("date-time", formatCheckerFunc(func(v interface{}) bool {
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	}))
+
+	RegisterFormat("date", formatCheckerFunc(func(v interface{}) bool {
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	}))
+
+	RegisterFormat("duration", formatCheckerFunc(func(v interface{}) bool {
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+		_, err := time.ParseDuration(s)
+		return err == nil
+	}))
+
+	RegisterFormat("uri", formatCheckerFunc(func(v interface{}) bool {
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+		u, err := url.Parse(s)
+		return err == nil && u.IsAbs()
+	}))
+
+	RegisterFormat("email", formatCheckerFunc(func(v interface{}) bool {
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+		_, err := mail.ParseAddress(s)
+		return err == nil
+	}))
+
+	RegisterFormat("uuid", formatCheckerFunc(func(v interface{}) bool {
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+		return uuidPattern.MatchString(s)
+	}))
+
+	RegisterFormat("ipv4", formatCheckerFunc(func(v interface{}) bool {
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil
+	}))
+
+	RegisterFormat("ipv6", formatCheckerFunc(func(v interface{}) bool {
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() == nil
+	}))
+
+	RegisterFormat("hostname", formatCheckerFunc(func(v interface{}) bool {
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+		return hostnamePattern.MatchString(s)
+	}))
+
+	RegisterFormat("regex", formatCheckerFunc(func(v interface{}) bool {
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+		_, err := regexp.Compile(s)
+		return err == nil
+	}))
+}
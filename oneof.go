@@ -0,0 +1,55 @@
+package huma
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// oneOfRegistry maps an interface type to the concrete types registered as
+// its possible implementations via RegisterOneOf.
+var oneOfRegistry = map[reflect.Type][]reflect.Type{}
+
+// RegisterOneOf registers the concrete implementations of a Go interface
+// so that struct fields of that interface type generate a `oneOf` schema
+// referencing each implementation instead of being rejected as
+// unsupported. iface must be a nil pointer to the interface type, e.g.
+//
+//	huma.RegisterOneOf((*Shape)(nil), Circle{}, Square{})
+func RegisterOneOf(iface interface{}, impls ...interface{}) {
+	ifaceType := reflect.TypeOf(iface).Elem()
+
+	implTypes := make([]reflect.Type, len(impls))
+	for i, impl := range impls {
+		implTypes[i] = reflect.TypeOf(impl)
+	}
+
+	oneOfRegistry[ifaceType] = implTypes
+}
+
+// anyOfPrimitives maps the type names recognized in an `anyOf` struct tag
+// to their JSON Schema primitive schema.
+var anyOfPrimitives = map[string]*Schema{
+	"string":  {Type: "string"},
+	"int":     {Type: "integer"},
+	"integer": {Type: "integer"},
+	"number":  {Type: "number"},
+	"bool":    {Type: "boolean"},
+	"boolean": {Type: "boolean"},
+}
+
+// anyOfSchema builds an `anyOf` schema from a comma-separated list of
+// primitive type names, e.g. `anyOf:"int,string"` for the Kubernetes
+// int-or-string pattern.
+func anyOfSchema(tag string) (*Schema, error) {
+	s := &Schema{}
+	for _, name := range strings.Split(tag, ",") {
+		name = strings.TrimSpace(name)
+		prim, ok := anyOfPrimitives[name]
+		if !ok {
+			return nil, fmt.Errorf("anyOf: unsupported primitive type %q", name)
+		}
+		s.AnyOf = append(s.AnyOf, prim)
+	}
+	return s, nil
+}
@@ -0,0 +1,173 @@
+package huma
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// SchemaRegistry collects named component schemas as they are generated so
+// that repeated or self-referential struct types can be expressed as a
+// `$ref` instead of being inlined again, which would otherwise duplicate
+// definitions (or recurse forever for cyclic types like linked lists and
+// trees). Create one with NewSchemaRegistry and reuse it across calls to
+// GenerateSchemaWithRegistry to share component definitions, e.g. across
+// every operation in an API.
+type SchemaRegistry struct {
+	// Definitions holds the fully generated schema for each component key
+	// registered so far. It corresponds to the OpenAPI 3 `components/schemas`
+	// map and can be embedded in a full spec document or emitted on its own
+	// via MarshalComponents.
+	Definitions map[string]*Schema
+
+	// inProgress tracks component keys whose schema is currently being
+	// built, so a type which references itself (directly or through a
+	// cycle of other types) gets a `$ref` back to the in-progress
+	// definition rather than recursing forever.
+	inProgress map[string]bool
+
+	// dedupe controls whether a component key that has already finished
+	// generating is returned as a `$ref` (true, for GenerateSchemaWithRegistry,
+	// where reg.Definitions is reachable by the caller) or regenerated
+	// inline (false, for GenerateSchema's internal registry, which is
+	// thrown away and so can't leave a dangling `$ref` behind). Either
+	// way, a cycle still breaks via `$ref` once a key is in progress.
+	dedupe bool
+}
+
+// NewSchemaRegistry creates an empty registry ready to be passed to
+// GenerateSchemaWithRegistry.
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		Definitions: make(map[string]*Schema),
+		inProgress:  make(map[string]bool),
+		dedupe:      true,
+	}
+}
+
+// newInlineRegistry creates a registry for GenerateSchema's own internal
+// use: it only breaks cycles via `$ref`, it never turns a repeated but
+// non-cyclic occurrence of a named struct into a `$ref`, since nothing
+// outside of GenerateSchema's single call can see reg.Definitions to
+// resolve one.
+func newInlineRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		Definitions: make(map[string]*Schema),
+		inProgress:  make(map[string]bool),
+		dedupe:      false,
+	}
+}
+
+// refPrefix is the JSON Schema pointer prefix used for OpenAPI 3 component
+// schemas.
+const refPrefix = "#/components/schemas/"
+
+// componentKey returns the stable name used to key a struct type's schema
+// in the registry and in `$ref` pointers, e.g. "pkg.TypeName".
+func componentKey(t reflect.Type) string {
+	name := t.Name()
+	if pkg := t.PkgPath(); pkg != "" {
+		if idx := strings.LastIndex(pkg, "/"); idx >= 0 {
+			pkg = pkg[idx+1:]
+		}
+		name = pkg + "." + name
+	}
+	return name
+}
+
+// refSchema returns a Schema which is nothing but a `$ref` pointer to the
+// given component key.
+func refSchema(key string) *Schema {
+	return &Schema{Ref: refPrefix + key}
+}
+
+// isComponentType returns true if t should be registered as a named
+// component schema rather than inlined, i.e. it's a struct with a name
+// that isn't one of the types with built-in special handling.
+func isComponentType(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.Name() == "" {
+		return false
+	}
+	switch t {
+	case timeType, uriType:
+		return false
+	}
+	return true
+}
+
+// schemaOrRef generates the schema for t. When reg.dedupe is set (i.e. reg
+// came from NewSchemaRegistry, for GenerateSchemaWithRegistry), named
+// struct types are de-duplicated: the first time a given component key is
+// seen its schema is generated and stored in reg.Definitions, and every
+// occurrence (including the one that triggered generation) is returned as
+// a `$ref` to that component. When reg.dedupe is unset (GenerateSchema's
+// own throwaway registry), a completed definition is instead regenerated
+// inline on every occurrence, since reg.Definitions isn't reachable by
+// the caller. Either way, a type which refers to itself, directly or via
+// a cycle, is broken by returning a `$ref` for any key still marked
+// in-progress.
+func schemaOrRef(t reflect.Type, reg *SchemaRegistry) (*Schema, error) {
+	if !isComponentType(t) {
+		return generateSchema(t, reg)
+	}
+
+	key := componentKey(t)
+
+	if reg.inProgress[key] {
+		return refSchema(key), nil
+	}
+
+	if reg.dedupe {
+		if _, ok := reg.Definitions[key]; ok {
+			return refSchema(key), nil
+		}
+	}
+
+	reg.inProgress[key] = true
+	s, err := generateSchema(t, reg)
+	delete(reg.inProgress, key)
+	if err != nil {
+		return nil, err
+	}
+
+	reg.Definitions[key] = s
+
+	if reg.dedupe {
+		return refSchema(key), nil
+	}
+	return s, nil
+}
+
+// detachRef ensures a field-level schema can safely carry sibling
+// keywords like `description` or `nullable`. A bare `$ref` schema can't -
+// JSON Schema and OpenAPI 3.0 both ignore keywords alongside `$ref` - so
+// it's wrapped in `allOf`, the same trick go-swagger uses, before the
+// caller attaches any per-field metadata.
+func detachRef(s *Schema) *Schema {
+	if s.Ref == "" {
+		return s
+	}
+	return &Schema{AllOf: []*Schema{s}}
+}
+
+// MarshalComponents returns the OpenAPI 3 `components` object containing
+// every schema collected in the registry so far, e.g. for embedding
+// alongside paths in a full spec document.
+func (r *SchemaRegistry) MarshalComponents() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"components": map[string]interface{}{
+			"schemas": r.Definitions,
+		},
+	})
+}
+
+// GenerateSchemaWithRegistry is like GenerateSchema but shares named
+// component schemas across calls via reg instead of inlining them: each
+// named struct type is generated at most once and every reference to it
+// (including the top-level call, and cycles in recursive types) is
+// returned as a `$ref` into reg.Definitions. Use reg.MarshalComponents to
+// emit the collected component schemas, e.g. as the `components` block of
+// an OpenAPI 3 document.
+func GenerateSchemaWithRegistry(t reflect.Type, reg *SchemaRegistry) (*Schema, error) {
+	return schemaOrRef(t, reg)
+}
@@ -34,34 +34,58 @@ func getTagValue(s *Schema, value string) (interface{}, error) {
 
 // Schema represents a JSON Schema which can be generated from Go structs
 type Schema struct {
-	Type             string             `json:"type,omitempty"`
-	Description      string             `json:"description,omitempty"`
-	Items            *Schema            `json:"items,omitempty"`
-	Properties       map[string]*Schema `json:"properties,omitempty"`
-	Required         []string           `json:"required,omitempty"`
-	Format           string             `json:"format,omitempty"`
-	Enum             []interface{}      `json:"enum,omitempty"`
-	Default          interface{}        `json:"default,omitempty"`
-	Example          interface{}        `json:"example,omitempty"`
-	Minimum          *int               `json:"minimum,omitempty"`
-	ExclusiveMinimum *int               `json:"exclusiveMinimum,omitempty"`
-	Maximum          *int               `json:"maximum,omitempty"`
-	ExclusiveMaximum *int               `json:"exclusiveMaximum,omitempty"`
-	MultipleOf       int                `json:"multipleOf,omitempty"`
-	MinLength        *int               `json:"minLength,omitempty"`
-	MaxLength        *int               `json:"maxLength,omitempty"`
-	Pattern          string             `json:"pattern,omitempty"`
-	MinItems         *int               `json:"minItems,omitempty"`
-	MaxItems         *int               `json:"maxItems,omitempty"`
-	UniqueItems      bool               `json:"uniqueItems,omitempty"`
-	MinProperties    *int               `json:"minProperties,omitempty"`
-	MaxProperties    *int               `json:"maxProperties,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Example              interface{}        `json:"example,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	MultipleOf           *float64           `json:"multipleOf,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	UniqueItems          bool               `json:"uniqueItems,omitempty"`
+	MinProperties        *int               `json:"minProperties,omitempty"`
+	MaxProperties        *int               `json:"maxProperties,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	PatternProperties    map[string]*Schema `json:"patternProperties,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	ReadOnly             bool               `json:"readOnly,omitempty"`
+	WriteOnly            bool               `json:"writeOnly,omitempty"`
+	Deprecated           bool               `json:"deprecated,omitempty"`
 }
 
 // GenerateSchema creates a JSON schema for a Go type. Struct field tags
 // can be used to provide additional metadata such as descriptions and
-// validation.
+// validation. Named struct types are inlined, same as the rest of the
+// schema, except for self-referential types (e.g. a tree or linked
+// list), whose cycle is broken with a `$ref` since inlining it would
+// recurse forever. Use GenerateSchemaWithRegistry instead to get
+// `$ref`-based component schemas shared across multiple calls.
 func GenerateSchema(t reflect.Type) (*Schema, error) {
+	return generateSchema(t, newInlineRegistry())
+}
+
+// generateSchema is the recursive workhorse behind GenerateSchema and
+// GenerateSchemaWithRegistry. It always inlines the schema for t itself;
+// callers that want named struct types de-duplicated as `$ref`s should go
+// through schemaOrRef instead, which is what happens for every nested
+// field, slice element, etc.
+func generateSchema(t reflect.Type, reg *SchemaRegistry) (*Schema, error) {
 	schema := &Schema{}
 
 	if t == ipType {
@@ -89,16 +113,41 @@ func GenerateSchema(t reflect.Type) (*Schema, error) {
 			jsonTags := strings.Split(f.Tag.Get("json"), ",")
 
 			name := f.Name
-			if len(jsonTags) > 0 {
+			if jsonTags[0] != "" {
 				name = jsonTags[0]
 			}
 
-			s, err := GenerateSchema(f.Type)
+			if f.Anonymous && jsonTags[0] == "" {
+				// Embedded struct: contribute its schema via allOf
+				// rather than flattening its fields into this schema
+				// or nesting it under its type name.
+				embedded, err := schemaOrRef(f.Type, reg)
+				if err != nil {
+					return nil, err
+				}
+				schema.AllOf = append(schema.AllOf, embedded)
+				continue
+			}
+
+			var s *Schema
+			var err error
+			if anyOf, ok := f.Tag.Lookup("anyOf"); ok {
+				s, err = anyOfSchema(anyOf)
+			} else {
+				s, err = schemaOrRef(f.Type, reg)
+			}
 			if err != nil {
 				return nil, err
 			}
+			// A bare `$ref` can't carry the tag-driven metadata below as
+			// sibling keys, so detach it into an allOf wrapper first.
+			s = detachRef(s)
 			properties[name] = s
 
+			if f.Type.Kind() == reflect.Ptr {
+				s.Nullable = true
+			}
+
 			if t, ok := f.Tag.Lookup("description"); ok {
 				s.Description = t
 			}
@@ -123,6 +172,9 @@ func GenerateSchema(t reflect.Type) (*Schema, error) {
 				if err != nil {
 					return nil, err
 				}
+				if err := checkFormatValue(s, "default", t, v); err != nil {
+					return nil, err
+				}
 
 				s.Default = v
 			}
@@ -132,12 +184,15 @@ func GenerateSchema(t reflect.Type) (*Schema, error) {
 				if err != nil {
 					return nil, err
 				}
+				if err := checkFormatValue(s, "example", t, v); err != nil {
+					return nil, err
+				}
 
 				s.Example = v
 			}
 
 			if t, ok := f.Tag.Lookup("minimum"); ok {
-				min, err := strconv.Atoi(t)
+				min, err := strconv.ParseFloat(t, 64)
 				if err != nil {
 					return nil, err
 				}
@@ -145,7 +200,7 @@ func GenerateSchema(t reflect.Type) (*Schema, error) {
 			}
 
 			if t, ok := f.Tag.Lookup("exclusiveMinimum"); ok {
-				min, err := strconv.Atoi(t)
+				min, err := strconv.ParseFloat(t, 64)
 				if err != nil {
 					return nil, err
 				}
@@ -153,7 +208,7 @@ func GenerateSchema(t reflect.Type) (*Schema, error) {
 			}
 
 			if t, ok := f.Tag.Lookup("maximum"); ok {
-				max, err := strconv.Atoi(t)
+				max, err := strconv.ParseFloat(t, 64)
 				if err != nil {
 					return nil, err
 				}
@@ -161,7 +216,7 @@ func GenerateSchema(t reflect.Type) (*Schema, error) {
 			}
 
 			if t, ok := f.Tag.Lookup("exclusiveMaximum"); ok {
-				max, err := strconv.Atoi(t)
+				max, err := strconv.ParseFloat(t, 64)
 				if err != nil {
 					return nil, err
 				}
@@ -169,11 +224,31 @@ func GenerateSchema(t reflect.Type) (*Schema, error) {
 			}
 
 			if t, ok := f.Tag.Lookup("multipleOf"); ok {
-				mof, err := strconv.Atoi(t)
+				mof, err := strconv.ParseFloat(t, 64)
 				if err != nil {
 					return nil, err
 				}
-				s.MultipleOf = mof
+				s.MultipleOf = &mof
+			}
+
+			if t, ok := f.Tag.Lookup("title"); ok {
+				s.Title = t
+			}
+
+			if t, ok := f.Tag.Lookup("readOnly"); ok {
+				s.ReadOnly = t == "true"
+			}
+
+			if t, ok := f.Tag.Lookup("writeOnly"); ok {
+				s.WriteOnly = t == "true"
+			}
+
+			if t, ok := f.Tag.Lookup("deprecated"); ok {
+				s.Deprecated = t == "true"
+			}
+
+			if t, ok := f.Tag.Lookup("nullable"); ok {
+				s.Nullable = t == "true"
 			}
 
 			if t, ok := f.Tag.Lookup("minLength"); ok {
@@ -232,7 +307,9 @@ func GenerateSchema(t reflect.Type) (*Schema, error) {
 				s.MaxProperties = &max
 			}
 
-			optional := false
+			// A pointer field is nullable, so - like an explicit
+			// omitempty - it's not required to be present.
+			optional := f.Type.Kind() == reflect.Ptr
 			for _, tag := range jsonTags[1:] {
 				if tag == "omitempty" {
 					optional = true
@@ -252,10 +329,28 @@ func GenerateSchema(t reflect.Type) (*Schema, error) {
 		}
 
 	case reflect.Map:
-		// pass
+		schema.Type = "object"
+		elem, err := schemaOrRef(t.Elem(), reg)
+		if err != nil {
+			return nil, err
+		}
+
+		switch t.Key().Kind() {
+		case reflect.String:
+			schema.AdditionalProperties = elem
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			// JSON object keys are always strings, so a map with a
+			// signed integer key type accepts any string that looks
+			// like one, negative sign included.
+			schema.PatternProperties = map[string]*Schema{"^-?[0-9]+$": elem}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			schema.PatternProperties = map[string]*Schema{"^[0-9]+$": elem}
+		default:
+			return nil, fmt.Errorf("unsupported map key type %s from %s", t.Key().Kind(), t)
+		}
 	case reflect.Slice, reflect.Array:
 		schema.Type = "array"
-		s, err := GenerateSchema(t.Elem())
+		s, err := schemaOrRef(t.Elem(), reg)
 		if err != nil {
 			return nil, err
 		}
@@ -266,7 +361,7 @@ func GenerateSchema(t reflect.Type) (*Schema, error) {
 		}, nil
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		// Unsigned integers can't be negative.
-		min := 0
+		min := 0.0
 		return &Schema{
 			Type:    "integer",
 			Minimum: &min,
@@ -278,7 +373,24 @@ func GenerateSchema(t reflect.Type) (*Schema, error) {
 	case reflect.String:
 		return &Schema{Type: "string"}, nil
 	case reflect.Ptr:
-		return GenerateSchema(t.Elem())
+		return schemaOrRef(t.Elem(), reg)
+	case reflect.Interface:
+		impls := oneOfRegistry[t]
+		if len(impls) == 0 {
+			// No registered implementations: accept any value. A field
+			// with an `anyOf` tag is handled by the caller instead.
+			return &Schema{}, nil
+		}
+
+		s := &Schema{}
+		for _, impl := range impls {
+			implSchema, err := schemaOrRef(impl, reg)
+			if err != nil {
+				return nil, err
+			}
+			s.OneOf = append(s.OneOf, implSchema)
+		}
+		return s, nil
 	default:
 		return nil, fmt.Errorf("unsupported type %s from %s", t.Kind(), t)
 	}
@@ -0,0 +1,124 @@
+package huma
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+type mapTestAddress struct {
+	City string `json:"city"`
+}
+
+type mapTestBook struct {
+	// Pages maps chapter number to its title.
+	Pages map[int]string `json:"pages"`
+	// Addresses maps a label to a nested struct, so it should
+	// participate in the $ref registry like any other struct field.
+	Addresses map[string]mapTestAddress `json:"addresses"`
+}
+
+func TestGenerateSchemaMapAdditionalProperties(t *testing.T) {
+	s, err := GenerateSchema(reflect.TypeOf(map[string]int{}))
+	if err != nil {
+		t.Fatalf("GenerateSchema: %v", err)
+	}
+	if s.Type != "object" {
+		t.Fatalf("Type = %q, want object", s.Type)
+	}
+	if s.AdditionalProperties == nil || s.AdditionalProperties.Type != "integer" {
+		t.Fatalf("AdditionalProperties = %+v, want integer schema", s.AdditionalProperties)
+	}
+}
+
+func TestGenerateSchemaMapIntKeyPatternProperties(t *testing.T) {
+	s, err := GenerateSchema(reflect.TypeOf(map[int]string{}))
+	if err != nil {
+		t.Fatalf("GenerateSchema: %v", err)
+	}
+
+	pattern := "^-?[0-9]+$"
+	elem, ok := s.PatternProperties[pattern]
+	if !ok {
+		t.Fatalf("PatternProperties = %+v, want key %q", s.PatternProperties, pattern)
+	}
+	if elem.Type != "string" {
+		t.Fatalf("pattern property schema = %+v, want string", elem)
+	}
+
+	if err := elem.Validate("chapter one"); err != nil {
+		t.Fatalf("elem.Validate: %v", err)
+	}
+
+	// A signed int key must accept negative-looking keys too.
+	if !regexp.MustCompile(pattern).MatchString("-3") {
+		t.Fatalf("pattern %q should match negative key %q", pattern, "-3")
+	}
+}
+
+func TestGenerateSchemaMapUintKeyPatternProperties(t *testing.T) {
+	s, err := GenerateSchema(reflect.TypeOf(map[uint]string{}))
+	if err != nil {
+		t.Fatalf("GenerateSchema: %v", err)
+	}
+
+	if _, ok := s.PatternProperties["^[0-9]+$"]; !ok {
+		t.Fatalf("PatternProperties = %+v, want unsigned key pattern", s.PatternProperties)
+	}
+}
+
+func TestGenerateSchemaNestedMapOfStructsUsesRegistry(t *testing.T) {
+	reg := NewSchemaRegistry()
+	s, err := GenerateSchemaWithRegistry(reflect.TypeOf(mapTestBook{}), reg)
+	if err != nil {
+		t.Fatalf("GenerateSchemaWithRegistry: %v", err)
+	}
+
+	bookKey := componentKey(reflect.TypeOf(mapTestBook{}))
+	book, ok := reg.Definitions[bookKey]
+	if !ok {
+		t.Fatalf("registry missing %q: %+v", bookKey, reg.Definitions)
+	}
+
+	addresses := book.Properties["addresses"]
+	if addresses == nil || addresses.AdditionalProperties == nil {
+		t.Fatalf("addresses property = %+v, want an object with additionalProperties", addresses)
+	}
+
+	addressKey := componentKey(reflect.TypeOf(mapTestAddress{}))
+	ref := addresses.AdditionalProperties.Ref
+	if ref != refPrefix+addressKey {
+		t.Fatalf("additionalProperties.Ref = %q, want %q", ref, refPrefix+addressKey)
+	}
+	if _, ok := reg.Definitions[addressKey]; !ok {
+		t.Fatalf("registry missing nested %q: %+v", addressKey, reg.Definitions)
+	}
+
+	// The top-level call itself must come back as a $ref too, reachable
+	// through reg.Definitions - not a schema the caller has no way to
+	// resolve, as GenerateSchemaWithRegistry would otherwise produce.
+	if s.Ref != refPrefix+bookKey {
+		t.Fatalf("top-level schema = %+v, want $ref to %q", s, bookKey)
+	}
+}
+
+func TestGenerateSchemaNestedStructStaysReachable(t *testing.T) {
+	// GenerateSchema (unlike GenerateSchemaWithRegistry) must keep
+	// nested named structs inlined, since its registry is thrown away
+	// and a caller would have no way to resolve a dangling $ref.
+	s, err := GenerateSchema(reflect.TypeOf(mapTestBook{}))
+	if err != nil {
+		t.Fatalf("GenerateSchema: %v", err)
+	}
+
+	addresses := s.Properties["addresses"]
+	if addresses == nil || addresses.AdditionalProperties == nil {
+		t.Fatalf("addresses property = %+v, want an object with additionalProperties", addresses)
+	}
+	if addresses.AdditionalProperties.Ref != "" {
+		t.Fatalf("additionalProperties = %+v, want inlined schema, not a $ref", addresses.AdditionalProperties)
+	}
+	if addresses.AdditionalProperties.Type != "object" {
+		t.Fatalf("additionalProperties.Type = %q, want object", addresses.AdditionalProperties.Type)
+	}
+}
@@ -0,0 +1,250 @@
+package huma
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Validate checks v, typically the result of unmarshaling JSON into an
+// interface{}, against the schema's type and its min/max, length,
+// pattern, enum, and format constraints, returning a descriptive error
+// for the first violation found. It recurses into Properties and Items
+// and applies allOf, anyOf and oneOf; object members not listed in
+// Properties, including those covered by additionalProperties or
+// patternProperties, are not checked.
+//
+// It does not resolve `$ref`s: a schema built with GenerateSchema never
+// contains one except to break a recursive type's cycle, but a schema
+// built with GenerateSchemaWithRegistry does, and validating it needs the
+// registry its component definitions live in - use ValidateWithRegistry
+// for that.
+func (s *Schema) Validate(v interface{}) error {
+	return s.ValidateWithRegistry(v, nil)
+}
+
+// ValidateWithRegistry is like Validate but resolves any `$ref` (and the
+// `allOf`-wrapped `$ref` that field-level metadata produces) against
+// reg.Definitions before checking v, so schemas produced by
+// GenerateSchemaWithRegistry validate correctly. reg may be nil if s is
+// known not to contain a `$ref`, e.g. one built with plain GenerateSchema
+// for a type that doesn't refer to itself.
+func (s *Schema) ValidateWithRegistry(v interface{}, reg *SchemaRegistry) error {
+	if s == nil {
+		return nil
+	}
+
+	if s.Ref != "" {
+		if reg == nil {
+			return fmt.Errorf("cannot validate $ref %q without a SchemaRegistry", s.Ref)
+		}
+		target, ok := reg.Definitions[strings.TrimPrefix(s.Ref, refPrefix)]
+		if !ok {
+			return fmt.Errorf("$ref %q not found in registry", s.Ref)
+		}
+		return target.ValidateWithRegistry(v, reg)
+	}
+
+	for _, sub := range s.AllOf {
+		if err := sub.ValidateWithRegistry(v, reg); err != nil {
+			return err
+		}
+	}
+
+	if len(s.OneOf) > 0 {
+		matches := 0
+		for _, sub := range s.OneOf {
+			if err := sub.ValidateWithRegistry(v, reg); err == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return fmt.Errorf("value %v matches %d of oneOf's %d subschemas, want exactly 1", v, matches, len(s.OneOf))
+		}
+	}
+
+	if len(s.AnyOf) > 0 {
+		matches := false
+		for _, sub := range s.AnyOf {
+			if err := sub.ValidateWithRegistry(v, reg); err == nil {
+				matches = true
+				break
+			}
+		}
+		if !matches {
+			return fmt.Errorf("value %v matches none of anyOf's %d subschemas", v, len(s.AnyOf))
+		}
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if reflect.DeepEqual(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("value %v is not one of the allowed enum values", v)
+		}
+	}
+
+	switch s.Type {
+	case "object":
+		if err := s.validateObject(v, reg); err != nil {
+			return err
+		}
+	case "array":
+		if err := s.validateArray(v, reg); err != nil {
+			return err
+		}
+	case "string":
+		if err := s.validateString(v); err != nil {
+			return err
+		}
+	case "integer", "number":
+		if err := s.validateNumber(v); err != nil {
+			return err
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("expected a boolean, got %T", v)
+		}
+	}
+
+	if s.Format != "" {
+		if checker, ok := formatCheckers[s.Format]; ok && !checker.IsFormat(v) {
+			return fmt.Errorf("value %v does not match format %q", v, s.Format)
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) validateObject(v interface{}, reg *SchemaRegistry) error {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected an object, got %T", v)
+	}
+
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			return fmt.Errorf("missing required property %q", name)
+		}
+	}
+
+	if s.MinProperties != nil && len(obj) < *s.MinProperties {
+		return fmt.Errorf("object has %d properties, want at least %d", len(obj), *s.MinProperties)
+	}
+	if s.MaxProperties != nil && len(obj) > *s.MaxProperties {
+		return fmt.Errorf("object has %d properties, want at most %d", len(obj), *s.MaxProperties)
+	}
+
+	for name, propSchema := range s.Properties {
+		val, ok := obj[name]
+		if !ok {
+			continue
+		}
+		if err := propSchema.ValidateWithRegistry(val, reg); err != nil {
+			return fmt.Errorf("property %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) validateArray(v interface{}, reg *SchemaRegistry) error {
+	arr, ok := v.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected an array, got %T", v)
+	}
+
+	if s.MinItems != nil && len(arr) < *s.MinItems {
+		return fmt.Errorf("array has %d items, want at least %d", len(arr), *s.MinItems)
+	}
+	if s.MaxItems != nil && len(arr) > *s.MaxItems {
+		return fmt.Errorf("array has %d items, want at most %d", len(arr), *s.MaxItems)
+	}
+
+	if s.UniqueItems {
+		for i := range arr {
+			for j := i + 1; j < len(arr); j++ {
+				if reflect.DeepEqual(arr[i], arr[j]) {
+					return fmt.Errorf("array items must be unique")
+				}
+			}
+		}
+	}
+
+	if s.Items != nil {
+		for _, item := range arr {
+			if err := s.Items.ValidateWithRegistry(item, reg); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) validateString(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("expected a string, got %T", v)
+	}
+
+	if s.MinLength != nil && len(str) < *s.MinLength {
+		return fmt.Errorf("string length %d is less than minLength %d", len(str), *s.MinLength)
+	}
+	if s.MaxLength != nil && len(str) > *s.MaxLength {
+		return fmt.Errorf("string length %d is greater than maxLength %d", len(str), *s.MaxLength)
+	}
+	if s.Pattern != "" {
+		matched, err := regexp.MatchString(s.Pattern, str)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			return fmt.Errorf("string %q does not match pattern %q", str, s.Pattern)
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) validateNumber(v interface{}) error {
+	var f float64
+	switch n := v.(type) {
+	case float64:
+		f = n
+	case int:
+		f = float64(n)
+	case int64:
+		f = float64(n)
+	default:
+		return fmt.Errorf("expected a number, got %T", v)
+	}
+	if s.Type == "integer" && f != math.Trunc(f) {
+		return fmt.Errorf("expected an integer, got %v", f)
+	}
+
+	if s.Minimum != nil && f < *s.Minimum {
+		return fmt.Errorf("value %v is less than minimum %v", f, *s.Minimum)
+	}
+	if s.ExclusiveMinimum != nil && f <= *s.ExclusiveMinimum {
+		return fmt.Errorf("value %v is not greater than exclusive minimum %v", f, *s.ExclusiveMinimum)
+	}
+	if s.Maximum != nil && f > *s.Maximum {
+		return fmt.Errorf("value %v is greater than maximum %v", f, *s.Maximum)
+	}
+	if s.ExclusiveMaximum != nil && f >= *s.ExclusiveMaximum {
+		return fmt.Errorf("value %v is not less than exclusive maximum %v", f, *s.ExclusiveMaximum)
+	}
+	if s.MultipleOf != nil && math.Mod(f, *s.MultipleOf) != 0 {
+		return fmt.Errorf("value %v is not a multiple of %v", f, *s.MultipleOf)
+	}
+
+	return nil
+}